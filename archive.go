@@ -0,0 +1,185 @@
+package main // Declare the main package
+
+import ( // Import necessary packages
+	"archive/tar"   // For reading .tar and .tar.gz entries
+	"archive/zip"   // For reading .zip entries
+	"compress/gzip" // For decompressing .tar.gz and plain .gz files
+	"fmt"           // For formatted errors
+	"io"            // For streaming archive entries into extractURLsFromFile
+	"log"           // For logging skipped/unsafe entries
+	"os"            // For opening archive files and checking the path separator
+	"path/filepath" // For computing and validating entry paths
+	"strings"       // For extension matching
+)
+
+// isArchiveFile reports whether path looks like one of the archive formats this tool
+// can read .tsv entries out of directly, without ever extracting anything to disk.
+func isArchiveFile(path string) bool {
+	lower := strings.ToLower(path) // Match case-insensitively
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"): // gzip-compressed tarball
+		return true
+	case strings.HasSuffix(lower, ".tar"): // Plain tarball
+		return true
+	case strings.HasSuffix(lower, ".zip"): // Zip archive
+		return true
+	case strings.HasSuffix(lower, ".gz"): // A single gzip-compressed file
+		return true
+	default:
+		return false // Not a recognized archive extension
+	}
+}
+
+// extractURLsFromArchive dispatches path to the reader for its archive format and returns
+// the URLs found across every .tsv entry it contains.
+func extractURLsFromArchive(path string, mode parserMode) ([]urlRecord, error) {
+	lower := strings.ToLower(path) // Match case-insensitively
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return extractURLsFromTarGz(path, mode) // Decompress, then walk as a tar
+	case strings.HasSuffix(lower, ".tar"):
+		return extractURLsFromTar(path, mode) // Walk directly as a tar
+	case strings.HasSuffix(lower, ".zip"):
+		return extractURLsFromZip(path, mode) // Walk as a zip
+	case strings.HasSuffix(lower, ".gz"):
+		return extractURLsFromPlainGzip(path, mode) // Decompress a single file
+	default:
+		return nil, fmt.Errorf("unsupported archive format: %s", path) // Should be unreachable given isArchiveFile
+	}
+}
+
+// extractURLsFromTarGz decompresses path with gzip and walks the result as a tar archive.
+func extractURLsFromTarGz(path string, mode parserMode) ([]urlRecord, error) {
+	file, err := os.Open(path) // Open the archive
+	if err != nil {            // Handle error
+		return nil, fmt.Errorf("could not open archive %s: %v", path, err) // Return formatted error
+	}
+	defer file.Close() // Close the underlying file when done
+
+	gzReader, err := gzip.NewReader(file) // Wrap it in a gzip reader
+	if err != nil {                       // If the gzip header is invalid
+		return nil, fmt.Errorf("could not decompress archive %s: %v", path, err) // Return formatted error
+	}
+	defer gzReader.Close() // Close the gzip reader when done
+
+	return extractURLsFromTarReader(gzReader, path, mode) // Walk the decompressed tar stream
+}
+
+// extractURLsFromTar walks path directly as an uncompressed tar archive.
+func extractURLsFromTar(path string, mode parserMode) ([]urlRecord, error) {
+	file, err := os.Open(path) // Open the archive
+	if err != nil {            // Handle error
+		return nil, fmt.Errorf("could not open archive %s: %v", path, err) // Return formatted error
+	}
+	defer file.Close() // Close the file when done
+
+	return extractURLsFromTarReader(file, path, mode) // Walk the tar stream
+}
+
+// extractURLsFromTarReader walks every entry of a tar stream read from r, running
+// extractURLsFromFile against each .tsv entry in turn.
+func extractURLsFromTarReader(r io.Reader, archivePath string, mode parserMode) ([]urlRecord, error) {
+	var records []urlRecord       // URL records collected across every .tsv entry
+	tarReader := tar.NewReader(r) // Tar entry iterator
+
+	for { // Walk every entry in the archive
+		header, err := tarReader.Next() // Advance to the next entry
+		if err == io.EOF {              // No more entries
+			break // Done walking the archive
+		}
+		if err != nil { // Any other error aborts the walk
+			return records, fmt.Errorf("error reading tar entries in %s: %v", archivePath, err) // Return what we have plus the error
+		}
+
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(strings.ToLower(header.Name), ".tsv") { // Only regular .tsv entries matter
+			continue // Skip directories and non-tsv members
+		}
+
+		if !isSafeEntryPath(archivePath, header.Name) { // Guard against ZipSlip-style path traversal
+			log.Printf("Skipping unsafe tar entry %q in %s", header.Name, archivePath) // Log and skip
+			continue                                                                   // Never trust an escaping entry name
+		}
+
+		label := archivePath + ":" + header.Name                         // Identify this entry in any error messages
+		entryRecords, err := extractURLsFromFile(tarReader, label, mode) // tarReader itself reads the current entry's bytes
+		if err != nil {                                                  // If extraction failed
+			log.Printf("Error extracting URLs from %s: %v", label, err) // Log and continue with the next entry
+			continue
+		}
+		records = append(records, entryRecords...) // Accumulate this entry's records
+	}
+
+	return records, nil // Return every URL record found across the archive
+}
+
+// extractURLsFromZip walks every .tsv entry in a zip archive at path.
+func extractURLsFromZip(path string, mode parserMode) ([]urlRecord, error) {
+	zipReader, err := zip.OpenReader(path) // Open the archive for random access
+	if err != nil {                        // Handle error
+		return nil, fmt.Errorf("could not open archive %s: %v", path, err) // Return formatted error
+	}
+	defer zipReader.Close() // Close the archive when done
+
+	var records []urlRecord // URL records collected across every .tsv entry
+
+	for _, entry := range zipReader.File { // Walk every entry in the archive
+		if entry.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name), ".tsv") { // Only regular .tsv entries matter
+			continue // Skip directories and non-tsv members
+		}
+
+		if !isSafeEntryPath(path, entry.Name) { // Guard against ZipSlip
+			log.Printf("Skipping unsafe zip entry %q in %s", entry.Name, path) // Log and skip
+			continue                                                           // Never trust an escaping entry name
+		}
+
+		label := path + ":" + entry.Name // Identify this entry in any error messages
+
+		reader, err := entry.Open() // Open a stream over the entry's bytes
+		if err != nil {             // If it couldn't be opened
+			log.Printf("Error opening %s: %v", label, err) // Log and continue
+			continue
+		}
+
+		entryRecords, err := extractURLsFromFile(reader, label, mode) // Extract URLs from the entry
+		reader.Close()                                                // Always close the entry reader
+		if err != nil {                                               // If extraction failed
+			log.Printf("Error extracting URLs from %s: %v", label, err) // Log and continue
+			continue
+		}
+		records = append(records, entryRecords...) // Accumulate this entry's records
+	}
+
+	return records, nil // Return every URL record found across the archive
+}
+
+// extractURLsFromPlainGzip decompresses a single gzip-compressed .tsv file at path.
+func extractURLsFromPlainGzip(path string, mode parserMode) ([]urlRecord, error) {
+	file, err := os.Open(path) // Open the archive
+	if err != nil {            // Handle error
+		return nil, fmt.Errorf("could not open archive %s: %v", path, err) // Return formatted error
+	}
+	defer file.Close() // Close the underlying file when done
+
+	gzReader, err := gzip.NewReader(file) // Wrap it in a gzip reader
+	if err != nil {                       // If the gzip header is invalid
+		return nil, fmt.Errorf("could not decompress archive %s: %v", path, err) // Return formatted error
+	}
+	defer gzReader.Close() // Close the gzip reader when done
+
+	label := strings.TrimSuffix(path, filepath.Ext(path))   // Drop the .gz suffix to see what's underneath
+	if !strings.HasSuffix(strings.ToLower(label), ".tsv") { // Only bother decompressing .tsv.gz files
+		return nil, nil // Not a compressed TSV; nothing to extract
+	}
+
+	return extractURLsFromFile(gzReader, label, mode) // Extract URLs from the decompressed content
+}
+
+// isSafeEntryPath guards against ZipSlip: it reports whether name would stay within a
+// hypothetical extraction root for archivePath once cleaned and joined. We never write
+// archive entries to disk today, but checking here keeps a future "-extract" mode safe
+// without having to remember to add the guard later.
+func isSafeEntryPath(archivePath, name string) bool {
+	dest := archivePath + ".extracted"                                                              // Hypothetical extraction root for this archive
+	joined := filepath.Join(dest, name)                                                             // Where this entry would land
+	return strings.HasPrefix(filepath.Clean(joined), filepath.Clean(dest)+string(os.PathSeparator)) // Must stay under dest
+}
@@ -0,0 +1,50 @@
+package main // Declare the main package
+
+import "testing" // Standard testing package
+
+// TestIsSafeEntryPathRejectsZipSlip guards the ZipSlip check that protects a future
+// "-extract" mode: an archive entry name that climbs out of the extraction root via ".."
+// must be rejected, even though nothing is written to disk today.
+func TestIsSafeEntryPathRejectsZipSlip(t *testing.T) {
+	tests := []struct {
+		name       string
+		archive    string
+		entry      string
+		wantIsSafe bool
+	}{
+		{"ordinary entry stays inside", "dump.tar.gz", "data/urls.tsv", true},
+		{"dot-dot escapes the extraction root", "dump.tar.gz", "../../etc/passwd", false},
+		{"nested dot-dot still escapes", "dump.tar.gz", "data/../../etc/passwd", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSafeEntryPath(tt.archive, tt.entry); got != tt.wantIsSafe {
+				t.Errorf("isSafeEntryPath(%q, %q) = %v, want %v", tt.archive, tt.entry, got, tt.wantIsSafe)
+			}
+		})
+	}
+}
+
+// TestIsArchiveFile checks the extension matching that decides whether listTSVFiles treats
+// a path as an archive worth walking for .tsv entries.
+func TestIsArchiveFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"dump.tar.gz", true},
+		{"dump.tgz", true},
+		{"dump.tar", true},
+		{"dump.zip", true},
+		{"urls.tsv.gz", true},
+		{"urls.tsv", false},
+		{"notes.txt", false},
+	}
+
+	for _, tt := range tests {
+		if got := isArchiveFile(tt.path); got != tt.want {
+			t.Errorf("isArchiveFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
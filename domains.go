@@ -0,0 +1,85 @@
+package main // Declare the main package
+
+import ( // Import necessary packages
+	"bufio"   // For reading domain list files line by line
+	"fmt"     // For formatted errors
+	"os"      // For opening domain list files
+	"strings" // For trimming and validating each line
+
+	"golang.org/x/net/publicsuffix" // For computing eTLD+1 registrable domains
+)
+
+const ( // Default asset paths and the domain the tool targets out of the box
+	defaultInclusionPath     = "assets/inclusion"  // Default allow-list location
+	defaultExclusionPath     = "assets/exclusion"  // Default block-list location
+	defaultRegistrableDomain = "documentcloud.org" // Always allowed unless explicitly excluded
+)
+
+// registrableDomain returns hostName's eTLD+1 (e.g. "s3.documentcloud.org" -> "documentcloud.org"),
+// falling back to hostName itself if a registrable domain can't be computed.
+func registrableDomain(hostName string) string {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(hostName) // Compute the registrable domain
+	if err != nil {                                           // If hostName has no known public suffix
+		return hostName // Fall back to the raw hostname
+	}
+	return domain // Return the computed eTLD+1
+}
+
+// loadDomainList reads one hostname or domain per non-blank, non-comment line from path.
+// A missing path is not an error (the list is simply empty); a malformed entry is.
+func loadDomainList(path string) ([]string, error) {
+	file, err := os.Open(path) // Open the list file
+	if err != nil {            // If it couldn't be opened
+		if os.IsNotExist(err) { // A missing optional list is fine
+			return nil, nil // Treat it as an empty list
+		}
+		return nil, fmt.Errorf("could not open domain list %s: %v", path, err) // Any other error is fatal
+	}
+	defer file.Close() // Close the file when done
+
+	var domains []string              // Collected, validated entries
+	scanner := bufio.NewScanner(file) // Scan the file line by line
+	lineNumber := 0                   // Track the line number for error messages
+	for scanner.Scan() {              // Iterate through each line
+		lineNumber++                                    // Count this line
+		line := strings.TrimSpace(scanner.Text())       // Trim surrounding whitespace
+		if line == "" || strings.HasPrefix(line, "#") { // Skip blank lines and comments
+			continue // Nothing to validate
+		}
+		if strings.ContainsAny(line, " \t") { // A hostname can never contain whitespace
+			return nil, fmt.Errorf("malformed entry in %s at line %d: %q", path, lineNumber, line) // Fail fast
+		}
+		domains = append(domains, strings.ToLower(line)) // Store the normalized entry
+	}
+
+	if err := scanner.Err(); err != nil { // Check for a scanning error
+		return nil, fmt.Errorf("error reading domain list %s: %v", path, err) // Propagate it
+	}
+
+	return domains, nil // Return the validated list
+}
+
+// isAllowedDomain decides whether hostName should be treated as belonging to the archive:
+// allowed when its eTLD+1 matches the default DocumentCloud domain or any inclusion entry,
+// unless hostName or its eTLD+1 appears in the exclusion list (exclusion always wins).
+func isAllowedDomain(hostName string, inclusion, exclusion []string) bool {
+	registrable := registrableDomain(hostName) // Compute once, compare against both lists
+
+	for _, blocked := range exclusion { // Exclusion is checked first and overrides everything else
+		if hostName == blocked || registrable == blocked { // Match on either the exact host or its eTLD+1
+			return false // Explicitly blocked
+		}
+	}
+
+	if registrable == defaultRegistrableDomain { // The tool's built-in default target
+		return true // Always allowed
+	}
+
+	for _, allowed := range inclusion { // Check the user-supplied allow-list
+		if hostName == allowed || registrable == allowed { // Match on either the exact host or its eTLD+1
+			return true // Explicitly allowed
+		}
+	}
+
+	return false // Not covered by the default domain or any inclusion entry
+}
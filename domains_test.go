@@ -0,0 +1,82 @@
+package main // Declare the main package
+
+import (
+	"os"      // For writing the test fixture file
+	"testing" // Standard testing package
+)
+
+// TestIsAllowedDomain covers the publicsuffix-based eTLD+1 matching, the default
+// DocumentCloud domain, and the inclusion/exclusion override rules.
+func TestIsAllowedDomain(t *testing.T) {
+	inclusion := []string{"example.com"}
+	exclusion := []string{"blocked.documentcloud.org", "evil.com"}
+
+	tests := []struct {
+		name     string
+		hostName string
+		want     bool
+	}{
+		{"default domain is always allowed", "documentcloud.org", true},
+		{"subdomain of the default domain matches via eTLD+1", "s3.documentcloud.org", true},
+		{"another subdomain of the default domain", "beta.documentcloud.org", true},
+		{"inclusion entry is allowed", "example.com", true},
+		{"subdomain of an inclusion entry matches via eTLD+1", "www.example.com", true},
+		{"exclusion overrides the default domain", "blocked.documentcloud.org", false},
+		{"exclusion entry itself is blocked", "evil.com", false},
+		{"unrelated domain is not allowed", "unrelated.net", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAllowedDomain(tt.hostName, inclusion, exclusion); got != tt.want {
+				t.Errorf("isAllowedDomain(%q) = %v, want %v", tt.hostName, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRegistrableDomain checks the eTLD+1 computation used by isAllowedDomain.
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		hostName string
+		want     string
+	}{
+		{"documentcloud.org", "documentcloud.org"},
+		{"s3.documentcloud.org", "documentcloud.org"},
+		{"www.beta.documentcloud.org", "documentcloud.org"},
+	}
+
+	for _, tt := range tests {
+		if got := registrableDomain(tt.hostName); got != tt.want {
+			t.Errorf("registrableDomain(%q) = %q, want %q", tt.hostName, got, tt.want)
+		}
+	}
+}
+
+// TestLoadDomainListRejectsMalformedEntries checks that a whitespace-containing entry
+// (never a valid hostname) fails fast instead of being silently accepted.
+func TestLoadDomainListRejectsMalformedEntries(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/malformed"
+
+	contents := "example.com\nnot a hostname\n"
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("could not write test fixture: %v", err)
+	}
+
+	if _, err := loadDomainList(path); err == nil {
+		t.Fatal("loadDomainList accepted a malformed entry, want an error")
+	}
+}
+
+// TestLoadDomainListMissingFileIsEmpty checks that a missing, optional list file is not
+// treated as an error.
+func TestLoadDomainListMissingFileIsEmpty(t *testing.T) {
+	domains, err := loadDomainList("/nonexistent/path/to/a/list")
+	if err != nil {
+		t.Fatalf("loadDomainList returned unexpected error: %v", err)
+	}
+	if len(domains) != 0 {
+		t.Errorf("loadDomainList returned %v, want an empty list", domains)
+	}
+}
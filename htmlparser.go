@@ -0,0 +1,73 @@
+package main // Declare the main package
+
+import ( // Import necessary packages
+	"log"     // For logging invalid URLs, matching the regex path's behavior
+	"net/url" // For validating candidate URLs
+	"regexp"  // For the tag-structure heuristic in looksLikeHTML
+	"strings" // For detecting HTML-looking content and splitting srcset
+
+	"golang.org/x/net/html" // For tokenizing HTML content
+)
+
+// parserMode selects how extractURLsFromFile pulls URLs out of a line of input.
+type parserMode string
+
+const ( // The three modes selectable via -parser
+	parserAuto  parserMode = "auto"  // Use the HTML tokenizer for HTML-looking lines, regex otherwise
+	parserRegex parserMode = "regex" // Always use the regex extractor
+	parserHTML  parserMode = "html"  // Always use the HTML tokenizer
+)
+
+// tagPattern requires an actual opening tag (a "<" followed by a letter), not just a bare
+// "<" and ">" anywhere in the line, so that plain text like "5 < 10 and http://x > 3" isn't
+// mistaken for markup.
+var tagPattern = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+
+// looksLikeHTML is a cheap heuristic used by parserAuto to decide whether a line is
+// worth tokenizing as HTML rather than scanning with the regex.
+func looksLikeHTML(line string) bool {
+	return tagPattern.MatchString(line) // Good enough to catch markup without a full parse
+}
+
+// extractURLsFromHTML tokenizes content as HTML and returns every href, src, data-src,
+// and srcset URL it finds, entity-unescaped and validated the same way as the regex path.
+func extractURLsFromHTML(content string) []string {
+	var urls []string                                          // URLs discovered in this line
+	tokenizer := html.NewTokenizer(strings.NewReader(content)) // Tokenize the line
+
+	for { // Walk every token
+		tokenType := tokenizer.Next() // Advance to the next token
+		if tokenType == html.ErrorToken {
+			return urls // End of content (or malformed markup); return what we have
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue // Only start tags carry the attributes we care about
+		}
+
+		token := tokenizer.Token()        // Materialize the current tag
+		for _, attr := range token.Attr { // Scan its attributes
+			switch attr.Key {
+			case "href", "src", "data-src": // A single URL value
+				urls = appendValidURL(urls, attr.Val)
+			case "srcset": // One or more "url descriptor" pairs, comma-separated
+				for _, candidate := range strings.Split(attr.Val, ",") { // Each candidate source
+					fields := strings.Fields(strings.TrimSpace(candidate)) // First field is the URL
+					if len(fields) > 0 {
+						urls = appendValidURL(urls, fields[0])
+					}
+				}
+			}
+		}
+	}
+}
+
+// appendValidURL HTML-unescapes raw, validates it as a URL, and appends it to urls if valid.
+func appendValidURL(urls []string, raw string) []string {
+	unescaped := html.UnescapeString(raw)            // Resolve entities such as &amp;
+	parsedURL, err := url.ParseRequestURI(unescaped) // Validate the same way the regex path does
+	if err != nil {                                  // If invalid
+		log.Printf("Invalid URL skipped: %s\n", unescaped) // Log and skip it
+		return urls                                        // Leave urls unchanged
+	}
+	return append(urls, parsedURL.String()) // Add the valid URL to the list
+}
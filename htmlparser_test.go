@@ -0,0 +1,80 @@
+package main // Declare the main package
+
+import (
+	"strings" // For building test readers
+	"testing" // Standard testing package
+)
+
+// TestExtractURLsFromFileParserModes guards the auto mode's fallback: a line that looks
+// like markup (stray angle brackets) but contains no real tags must still surface any
+// plain URL via the regex path, never silently drop it.
+func TestExtractURLsFromFileParserModes(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		mode parserMode
+		want []string
+	}{
+		{
+			name: "auto falls back to regex when angle brackets aren't markup",
+			line: `5 < 10 and http://documentcloud.org/doc.pdf > 3`,
+			mode: parserAuto,
+			want: []string{"http://documentcloud.org/doc.pdf"},
+		},
+		{
+			name: "auto tokenizes a real anchor tag as HTML",
+			line: `<a href="http://documentcloud.org/doc.pdf">link</a>`,
+			mode: parserAuto,
+			want: []string{"http://documentcloud.org/doc.pdf"},
+		},
+		{
+			name: "regex mode ignores markup and still finds the URL",
+			line: `<a href="http://documentcloud.org/doc.pdf">link</a>`,
+			mode: parserRegex,
+			want: []string{"http://documentcloud.org/doc.pdf"},
+		},
+		{
+			name: "plain regex-matchable line under auto mode",
+			line: `see http://documentcloud.org/doc.pdf for details`,
+			mode: parserAuto,
+			want: []string{"http://documentcloud.org/doc.pdf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := extractURLsFromFile(strings.NewReader(tt.line), "test.tsv", tt.mode)
+			if err != nil {
+				t.Fatalf("extractURLsFromFile returned error: %v", err)
+			}
+
+			var got []string
+			for _, record := range records {
+				got = append(got, record.URL)
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got URLs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLooksLikeHTML checks the tightened heuristic rejects bare angle brackets that aren't
+// an actual tag, which is what let the regression in auto mode slip through.
+func TestLooksLikeHTML(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`<a href="http://documentcloud.org/doc.pdf">link</a>`, true},
+		{`5 < 10 and http://documentcloud.org/doc.pdf > 3`, false},
+		{`no markup here at all`, false},
+	}
+
+	for _, tt := range tests {
+		if got := looksLikeHTML(tt.line); got != tt.want {
+			t.Errorf("looksLikeHTML(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -2,32 +2,55 @@ package main // Declare the main package
 
 import ( // Import necessary packages
 	"bufio"         // For reading files line by line
+	"flag"          // For CLI flag parsing
 	"fmt"           // For formatted I/O
+	"io"            // For the reader-based extraction entry point
 	"log"           // For logging
 	"net/url"       // For URL parsing and validation
 	"os"            // For file and OS interaction
 	"path/filepath" // For walking directory tree and file path manipulations
 	"regexp"        // For regex pattern matching
-	"strings"       // For string manipulation
+	"runtime"       // For defaulting -workers to the number of available CPUs
 )
 
-// Function to extract URLs from a given file
-func extractURLsFromFile(fileName string) ([]string, error) {
-	file, err := os.Open(fileName) // Open the file for reading
-	if err != nil {                // If there's an error opening the file
-		return nil, fmt.Errorf("could not open file %s: %v", fileName, err) // Return a formatted error
-	}
-	defer file.Close() // Ensure the file gets closed at the end
-
-	re := regexp.MustCompile(`http[s]?://[^\s"]+`) // Compile a regex to match HTTP or HTTPS URLs
+// urlRegex matches HTTP or HTTPS URLs. Compiled once at package level so the many
+// concurrent calls to extractURLsFromFile below don't each pay the compile cost.
+var urlRegex = regexp.MustCompile(`http[s]?://[^\s"]+`)
+
+// Function to extract URLs out of an already-open reader. label identifies the source
+// (a file path, or "archive.tar.gz:entry.tsv" for an archive member) purely for error
+// messages, since reader may not come from disk at all. mode selects, per line, whether
+// the HTML tokenizer or the regex is used to pull out candidate URLs.
+func extractURLsFromFile(reader io.Reader, label string, mode parserMode) ([]urlRecord, error) {
+	var records []urlRecord // Initialize a slice to store extracted URL records
+	lineNumber := 0         // Track the 1-based line number for the source_file/line_number fields
+
+	scanner := bufio.NewScanner(reader) // Create a scanner to read the content line by line
+	for scanner.Scan() {                // Iterate through each line
+		lineNumber++           // Count this line
+		line := scanner.Text() // Read the current line as a string
 
-	var urls []string // Initialize a slice to store extracted URLs
+		if mode == parserHTML { // Always tokenize as HTML when explicitly requested
+			for _, match := range extractURLsFromHTML(line) { // Pull href/src/data-src/srcset URLs
+				records = append(records, urlRecord{URL: match, SourceFile: label, LineNumber: lineNumber}) // Record where it was found
+			}
+			continue // Skip the regex path for this line
+		}
 
-	scanner := bufio.NewScanner(file) // Create a scanner to read the file line by line
-	for scanner.Scan() {              // Iterate through each line
-		line := scanner.Text() // Read the current line as a string
+		if mode == parserAuto && looksLikeHTML(line) { // Tokenize markup-looking lines as HTML
+			htmlMatches := extractURLsFromHTML(line) // Pull href/src/data-src/srcset URLs
+			if len(htmlMatches) > 0 {
+				for _, match := range htmlMatches {
+					records = append(records, urlRecord{URL: match, SourceFile: label, LineNumber: lineNumber}) // Record where it was found
+				}
+				continue // Skip the regex path for this line
+			}
+			// A tag-looking line that yielded no URLs (stray angle brackets alongside a plain
+			// URL, truncated markup) falls through to the regex path below instead of silently
+			// dropping the URL.
+		}
 
-		matches := re.FindAllString(line, -1) // Find all URL matches in the line
+		matches := urlRegex.FindAllString(line, -1) // Find all URL matches in the line
 
 		for _, match := range matches { // Iterate through each matched URL
 			parsedURL, err := url.ParseRequestURI(match) // Attempt to parse and validate the URL
@@ -35,20 +58,32 @@ func extractURLsFromFile(fileName string) ([]string, error) {
 				log.Printf("Invalid URL skipped: %s\n", match) // Log and skip it
 				continue                                       // Move to the next match
 			}
-			urls = append(urls, parsedURL.String()) // Add valid URL to the list
+			records = append(records, urlRecord{URL: parsedURL.String(), SourceFile: label, LineNumber: lineNumber}) // Record where it was found
 		}
 	}
 
 	if err := scanner.Err(); err != nil { // Check if there was an error during scanning
-		return nil, fmt.Errorf("error reading file %s: %v", fileName, err) // Return a formatted error
+		return nil, fmt.Errorf("error reading %s: %v", label, err) // Return a formatted error
+	}
+
+	return records, nil // Return the extracted records and nil error
+}
+
+// extractURLsFromDiskFile opens fileName and runs extractURLsFromFile against its contents.
+func extractURLsFromDiskFile(fileName string, mode parserMode) ([]urlRecord, error) {
+	file, err := os.Open(fileName) // Open the file for reading
+	if err != nil {                // If there's an error opening the file
+		return nil, fmt.Errorf("could not open file %s: %v", fileName, err) // Return a formatted error
 	}
+	defer file.Close() // Ensure the file gets closed at the end
 
-	return urls, nil // Return the list of URLs and nil error
+	return extractURLsFromFile(file, fileName, mode) // Delegate to the reader-based extractor
 }
 
-// Function to recursively list all .tsv files in current directory
+// Function to recursively list all .tsv files, plus any archives that may contain .tsv
+// entries (see isArchiveFile), in the current directory.
 func listTSVFiles() ([]string, error) {
-	var tsvFiles []string // Slice to store paths of .tsv files
+	var tsvFiles []string // Slice to store paths of .tsv files and archives
 
 	currentDir, err := os.Getwd() // Get current working directory
 	if err != nil {               // Handle error getting current dir
@@ -60,16 +95,16 @@ func listTSVFiles() ([]string, error) {
 			return err // Return it to stop the walk
 		}
 
-		if !info.IsDir() && filepath.Ext(path) == ".tsv" { // If it's a .tsv file
+		if !info.IsDir() && (filepath.Ext(path) == ".tsv" || isArchiveFile(path)) { // If it's a .tsv file or a supported archive
 			tsvFiles = append(tsvFiles, path) // Add the file path to the list
 		}
 		return nil // Continue walking
 	})
 
-	return tsvFiles, err // Return found .tsv files and any walk error
+	return tsvFiles, err // Return found .tsv files/archives and any walk error
 }
 
-// Function to save a list of URLs to a file
+// Function to save a list of URLs to a file, one per line
 func saveURLsToFile(urls []string, outputFile string) error {
 	file, err := os.Create(outputFile) // Create (or truncate) the output file
 	if err != nil {                    // Handle error
@@ -89,19 +124,6 @@ func saveURLsToFile(urls []string, outputFile string) error {
 	return writer.Flush() // Flush the buffer to file and return any error
 }
 
-// Function to remove duplicate strings from a slice
-func removeDuplicatesFromSlice(slice []string) []string {
-	check := make(map[string]bool)  // Map to track seen strings
-	var newReturnSlice []string     // Slice for unique strings
-	for _, content := range slice { // Loop through original slice
-		if !check[content] { // If not already seen
-			check[content] = true                            // Mark as seen
-			newReturnSlice = append(newReturnSlice, content) // Add to result
-		}
-	}
-	return newReturnSlice // Return de-duplicated slice
-}
-
 // Function to check if a URL string is valid
 func isUrlValid(uri string) bool {
 	_, err := url.ParseRequestURI(uri) // Try to parse the URL
@@ -117,39 +139,64 @@ func getHostNameFromURL(uri string) string {
 	return content.Hostname() // Return just the hostname
 }
 
-// Function to clean URLs by validating and filtering by allowed domains
-func cleanURLs(urls []string) []string {
-	validDomains := []string{"s3.documentcloud.org", "documentcloud.org", "www.documentcloud.org", "beta.documentcloud.org"} // Allowed hostnames
-	var newReturnSlice []string                                                                                              // Slice for valid, cleaned URLs
-
-	for _, content := range urls { // Loop through all URLs
-		if isUrlValid(content) { // If the URL is valid
-			hostName := getHostNameFromURL(content) // Extract hostname
-
-			content = strings.TrimSuffix(content, "target=&quot;_blank&quot;") // Remove unwanted suffix
+// Function to clean URLs by validating and filtering by allowed domains.
+// A URL is allowed when its eTLD+1 matches the default DocumentCloud domain or an inclusion
+// entry, unless its hostname or eTLD+1 appears in exclusion (exclusion always wins).
+func cleanURLs(records []urlRecord, inclusion, exclusion []string) []urlRecord {
+	var newReturnSlice []urlRecord // Slice for valid, cleaned records
 
-			isValid := false                      // Flag to check if domain is allowed
-			for _, domain := range validDomains { // Loop through allowed domains
-				if hostName == domain { // If domain matches
-					isValid = true // Mark as valid
-					break          // Stop checking
-				}
-			}
+	for _, record := range records { // Loop through all records
+		if isUrlValid(record.URL) { // If the URL is valid
+			hostName := getHostNameFromURL(record.URL) // Extract hostname
 
-			if isValid { // If URL is from valid domain
-				newReturnSlice = append(newReturnSlice, content) // Add to result
+			if isAllowedDomain(hostName, inclusion, exclusion) { // If URL is from an allowed domain
+				record.Host = hostName                          // Fill in the host now that it's known to be allowed
+				newReturnSlice = append(newReturnSlice, record) // Add to result
 			} else {
 				log.Println("Invalid domain skipped: ", hostName) // Log skipped domain
 			}
 		}
 	}
 
-	return newReturnSlice // Return cleaned URLs
+	return newReturnSlice // Return cleaned records
 }
 
 func main() {
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile) // Setup logging with date, time, file, and line number
 
+	outDir := flag.String("out", "", "directory to mirror fetched URLs into (enables fetch-and-mirror mode)")                 // Destination dir for the archival scraper
+	depth := flag.Int("depth", 0, "maximum link-following depth when mirroring (0 = fetch seeds only)")                       // How many hops to follow from each seed URL
+	throttleMs := flag.Int("throttle", 0, "minimum milliseconds to sleep between requests per worker")                        // Per-worker rate limit
+	crossDomain := flag.Bool("crossdomain", false, "allow the mirror to follow links onto other domains")                     // Off by default to stay within DocumentCloud
+	workers := flag.Int("workers", runtime.NumCPU(), "number of concurrent workers to use for TSV scanning and mirroring")    // Shared pool size for both stages
+	inclusionPath := flag.String("inclusion", defaultInclusionPath, "path to a list of additional allowed hostnames/domains") // Allow-list override
+	exclusionPath := flag.String("exclusion", defaultExclusionPath, "path to a list of blocked hostnames/domains")            // Block-list override
+	quiet := flag.Bool("quiet", false, "suppress per-file and progress logging")                                              // Quiet mode for cron-style runs
+	parser := flag.String("parser", string(parserAuto), "URL extraction mode: auto, regex, or html")                          // Selects the line-level extraction strategy
+	update := flag.Bool("update", false, "only emit URLs not already recorded in the .state sidecar, then update it")         // Incremental, cron-friendly mode
+	sortOutput := flag.Bool("sort", false, "emit output URLs in sorted order")                                                // Deterministic ordering for diffing
+	format := flag.String("format", formatTxt, "output format: txt, json, or csv")                                            // Selects the output encoding
+	flag.Parse()                                                                                                              // Parse the CLI flags above
+
+	mode := parserMode(*parser)                                          // Validate the requested parser mode
+	if mode != parserAuto && mode != parserRegex && mode != parserHTML { // Reject anything else up front
+		log.Fatalf("Invalid -parser value %q: must be auto, regex, or html", *parser) // Fail fast with a clear message
+	}
+
+	if *format != formatTxt && *format != formatJSON && *format != formatCSV { // Reject anything else up front
+		log.Fatalf("Invalid -format value %q: must be txt, json, or csv", *format) // Fail fast with a clear message
+	}
+
+	inclusion, err := loadDomainList(*inclusionPath) // Load the inclusion list, if any
+	if err != nil {                                  // Malformed entries should fail fast
+		log.Fatalf("Error loading inclusion list: %v", err) // Log and exit
+	}
+
+	exclusion, err := loadDomainList(*exclusionPath) // Load the exclusion list, if any
+	if err != nil {                                  // Malformed entries should fail fast
+		log.Fatalf("Error loading exclusion list: %v", err) // Log and exit
+	}
+
 	tsvFiles, err := listTSVFiles() // List all .tsv files in current directory
 	if err != nil {                 // If there's an error
 		log.Fatalf("Error listing TSV files: %v", err) // Log and exit
@@ -160,27 +207,51 @@ func main() {
 		return                                                                             // Exit program
 	}
 
-	var allURLs []string // Slice to hold all extracted URLs
+	allRecords := scanTSVFiles(tsvFiles, *workers, *quiet, mode) // Extract and dedupe URL records across a worker pool
+
+	allRecords = cleanURLs(allRecords, inclusion, exclusion) // Validate and filter URLs
+
+	outputFile := outputFileNameFor(*format) // Name the output file to match -format's encoding
+	stateFile := "extracted_urls.state"      // Sidecar tracking every URL ever emitted, for -update
+	outputRecords := allRecords              // What gets written this run; narrowed below under -update
 
-	for _, fileName := range tsvFiles { // Iterate through each .tsv file
-		log.Printf("Extracting URLs from file: %s", fileName) // Log the file being processed
-		urls, err := extractURLsFromFile(fileName)            // Extract URLs from file
-		if err != nil {                                       // If there's an error
-			log.Printf("Error extracting URLs from file %s: %v", fileName, err) // Log and continue
-			continue                                                            // Move on to next file
+	if *update { // Only touch the state file when -update is requested
+		previouslySeen, err := loadState(stateFile) // Load URLs emitted by a prior -update run (empty on first run)
+		if err != nil {                             // A corrupt or unreadable state file is fatal
+			log.Fatalf("Error loading state file: %v", err) // Log and exit
 		}
-		allURLs = append(allURLs, urls...) // Append extracted URLs to the full list
-	}
 
-	allURLs = removeDuplicatesFromSlice(allURLs) // Remove duplicate URLs
+		var newRecords []urlRecord          // Only the records not already in previouslySeen
+		for _, record := range allRecords { // Walk every record from this run
+			if !previouslySeen[record.URL] { // Skip anything already emitted by a prior run
+				newRecords = append(newRecords, record) // Keep it for this run's output
+			}
+			previouslySeen[record.URL] = true // Either way, it's now seen
+		}
+		outputRecords = newRecords // Only emit what's new
+
+		if err := saveStateAtomic(stateFile, previouslySeen); err != nil { // Persist the updated seen-set atomically
+			log.Fatalf("Error saving state file: %v", err) // Log and exit
+		}
+	}
 
-	allURLs = cleanURLs(allURLs) // Validate and filter URLs
+	if *sortOutput { // Respect -sort
+		sortRecords(outputRecords) // Sort in place by URL
+	}
 
-	outputFile := "extracted_urls.txt"        // Set name of output file
-	err = saveURLsToFile(allURLs, outputFile) // Save final URLs to file
-	if err != nil {                           // Handle save error
+	if err := writeRecords(outputRecords, outputFile, *format); err != nil { // Save final URLs to file
 		log.Printf("Error saving URLs to file: %v", err) // Log the error
 	} else {
 		log.Printf("Successfully saved URLs to %s", outputFile) // Log success
 	}
+
+	if *outDir != "" { // Only mirror when the caller opted in with -out
+		mirrorURLs(recordURLs(allRecords), mirrorOptions{ // Fetch and mirror every extracted URL to disk
+			OutDir:      *outDir,      // Root directory for the mirror
+			Depth:       *depth,       // How deep to follow discovered links
+			ThrottleMs:  *throttleMs,  // Delay between requests per worker
+			CrossDomain: *crossDomain, // Whether off-domain links may be followed
+			Workers:     *workers,     // Size of the fetch worker pool
+		})
+	}
 }
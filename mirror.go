@@ -0,0 +1,277 @@
+package main // Declare the main package
+
+import ( // Import necessary packages
+	"fmt"           // For formatting the path-traversal error
+	"io"            // For copying response bodies to disk
+	"log"           // For structured per-URL logging
+	"net/http"      // For issuing the actual fetches
+	"net/url"       // For resolving and comparing URLs
+	"os"            // For writing mirrored files to disk
+	"path/filepath" // For turning a URL path into a filesystem path
+	"strings"       // For trimming and joining path fragments
+	"sync"          // For the visited-set mutex and completion tracking
+	"time"          // For the per-worker throttle
+
+	"golang.org/x/net/html" // For discovering links in fetched HTML pages
+)
+
+// mirrorOptions configures a single run of mirrorURLs.
+type mirrorOptions struct {
+	OutDir      string // Root directory the mirror is written under
+	Depth       int    // Maximum number of hops to follow from each seed URL
+	ThrottleMs  int    // Minimum milliseconds to sleep between requests per worker
+	CrossDomain bool   // Whether links pointing off the seed's domain may be followed
+	Workers     int    // Number of concurrent fetch workers
+}
+
+// mirrorJob is a single URL queued for fetching, along with how deep it was discovered.
+type mirrorJob struct {
+	URL   string // The URL to fetch
+	Depth int    // How many hops away from a seed this URL is
+}
+
+// jobQueue is an unbounded FIFO of pending mirror jobs. A fixed-capacity channel used both
+// as the queue workers drain and the queue workers feed back into (when a fetched page
+// discovers further links) can deadlock: if every worker is blocked sending a discovered
+// link while the buffer is full, nothing is left to receive and drain it. push never blocks,
+// so a worker producing new jobs can never deadlock behind the workers meant to consume them.
+type jobQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  []mirrorJob
+	closed bool
+}
+
+// newJobQueue returns an empty, open jobQueue.
+func newJobQueue() *jobQueue {
+	q := &jobQueue{}             // Zero-value queue
+	q.cond = sync.NewCond(&q.mu) // Wired to the queue's own mutex
+	return q
+}
+
+// push appends job to the queue and wakes one waiting worker. Never blocks.
+func (q *jobQueue) push(job mirrorJob) {
+	q.mu.Lock()
+	q.items = append(q.items, job) // Append to the tail
+	q.mu.Unlock()
+	q.cond.Signal() // Wake a worker blocked in pop, if any
+}
+
+// pop blocks until a job is available or the queue has been closed and drained, in which
+// case it returns (mirrorJob{}, false) to tell the caller to exit.
+func (q *jobQueue) pop() (mirrorJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.items) == 0 && !q.closed { // Wait for work or closure
+		q.cond.Wait()
+	}
+
+	if len(q.items) == 0 { // Closed with nothing left to drain
+		return mirrorJob{}, false
+	}
+
+	job := q.items[0]     // Take from the head
+	q.items = q.items[1:] // Drop it from the queue
+	return job, true
+}
+
+// close marks the queue closed and wakes every worker waiting in pop.
+func (q *jobQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.cond.Broadcast() // Wake every waiter so they can observe closure
+}
+
+// mirrorURLs fetches every URL in seeds (and, within Depth, links discovered inside them)
+// using a pool of Workers goroutines, writing each response body to a path under OutDir
+// that mirrors the URL's host and path. Per-URL failures are logged and do not abort the run.
+func mirrorURLs(seeds []string, opts mirrorOptions) {
+	if opts.Workers < 1 { // Guard against a nonsensical pool size
+		opts.Workers = 1 // Fall back to a single worker
+	}
+
+	jobs := newJobQueue() // Unbounded frontier; see jobQueue's doc comment for why
+	var wg sync.WaitGroup // Tracks jobs that are queued or in flight
+
+	var visitedMu sync.Mutex           // Guards the visited set below
+	visited := make(map[string]bool)   // Dedupes URLs across the whole run
+	seedHosts := make(map[string]bool) // Hosts of the original seed URLs, used for the cross-domain check
+	for _, seed := range seeds {       // Seed the queue and the seed-host set
+		if parsed, err := url.Parse(seed); err == nil { // Ignore unparsable seeds here; the fetch below will report them
+			seedHosts[parsed.Hostname()] = true // Remember this seed's host
+		}
+	}
+
+	for _, seed := range seeds { // Enqueue every seed URL at depth 0
+		visitedMu.Lock()   // Take the lock before touching visited
+		if visited[seed] { // Skip if somehow already queued
+			visitedMu.Unlock() // Release before continuing
+			continue           // Nothing to do
+		}
+		visited[seed] = true                      // Mark as queued
+		visitedMu.Unlock()                        // Release the lock
+		wg.Add(1)                                 // One more job in flight
+		jobs.push(mirrorJob{URL: seed, Depth: 0}) // Push onto the queue
+	}
+
+	go func() { // Closer goroutine: once every in-flight job is done, close the queue so workers exit
+		wg.Wait()    // Block until the pending count drops to zero
+		jobs.close() // Signal workers there is no more work
+	}()
+
+	var workersWg sync.WaitGroup        // Tracks the worker goroutines themselves
+	for i := 0; i < opts.Workers; i++ { // Start the fetch worker pool
+		workersWg.Add(1) // One more worker to wait on
+		go func() {      // Each worker pulls jobs until the queue closes
+			defer workersWg.Done() // Signal completion on exit
+			for {
+				job, ok := jobs.pop() // Pull the next job off the queue
+				if !ok {
+					return // Queue closed and drained; nothing left to do
+				}
+				fetchAndMirror(job, opts, seedHosts, jobs, &wg, &visitedMu, visited) // Handle it
+				if opts.ThrottleMs > 0 {                                             // Respect the configured throttle
+					time.Sleep(time.Duration(opts.ThrottleMs) * time.Millisecond) // Sleep before the next request
+				}
+				wg.Done() // This job is complete
+			}
+		}()
+	}
+
+	workersWg.Wait() // Wait for every worker to drain the queue and exit
+}
+
+// fetchAndMirror downloads a single job's URL, writes it under opts.OutDir, and (for HTML
+// responses) discovers further links to enqueue, subject to opts.Depth and opts.CrossDomain.
+func fetchAndMirror(job mirrorJob, opts mirrorOptions, seedHosts map[string]bool, jobs *jobQueue, wg *sync.WaitGroup, visitedMu *sync.Mutex, visited map[string]bool) {
+	resp, err := http.Get(job.URL) // Issue the request
+	if err != nil {                // If the request failed outright
+		log.Printf("mirror: error fetching %s: %v", job.URL, err) // Log and move on; never log.Fatalln here
+		return                                                    // Nothing more to do for this job
+	}
+	defer resp.Body.Close() // Always close the response body
+
+	if resp.StatusCode != http.StatusOK { // If the server returned a non-200 status
+		log.Printf("mirror: unexpected status %d fetching %s", resp.StatusCode, job.URL) // Log and move on
+		return                                                                           // Skip writing and link discovery
+	}
+
+	body, err := io.ReadAll(resp.Body) // Read the whole body; mirrored pages are expected to be reasonably sized
+	if err != nil {                    // If reading failed
+		log.Printf("mirror: error reading body of %s: %v", job.URL, err) // Log and move on
+		return                                                           // Nothing to write or parse
+	}
+
+	destPath, err := mirrorPathFor(opts.OutDir, job.URL) // Compute the on-disk path mirroring the URL
+	if err != nil {                                      // If the URL couldn't be turned into a path
+		log.Printf("mirror: error computing mirror path for %s: %v", job.URL, err) // Log and move on
+		return                                                                     // Nothing to write
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil { // Ensure the parent directory exists
+		log.Printf("mirror: error creating directory for %s: %v", job.URL, err) // Log and move on
+		return                                                                  // Can't write without the directory
+	}
+
+	if err := os.WriteFile(destPath, body, 0o644); err != nil { // Write the response body to its mirrored path
+		log.Printf("mirror: error writing %s: %v", destPath, err) // Log and move on
+		return                                                    // Nothing more to do
+	}
+
+	if job.Depth >= opts.Depth { // Stop discovering new links once the configured depth is reached
+		return // Do not parse this page for further links
+	}
+
+	contentType := resp.Header.Get("Content-Type")   // Only HTML responses are worth tokenizing for links
+	if !strings.Contains(contentType, "text/html") { // Skip non-HTML content types
+		return // Nothing to discover
+	}
+
+	for _, link := range discoverLinks(body, job.URL) { // Walk the discovered href/src URLs
+		parsed, err := url.Parse(link) // Resolve the link
+		if err != nil {                // Skip anything unparsable
+			continue // Move to the next link
+		}
+
+		if !opts.CrossDomain && !seedHosts[parsed.Hostname()] { // Enforce the cross-domain restriction
+			continue // Skip links that leave the seed domain(s)
+		}
+
+		visitedMu.Lock()   // Take the lock before checking/marking visited
+		if visited[link] { // Already queued or fetched
+			visitedMu.Unlock() // Release and skip
+			continue           // Nothing to do
+		}
+		visited[link] = true // Mark as queued
+		visitedMu.Unlock()   // Release the lock
+
+		wg.Add(1)                                             // One more job in flight
+		jobs.push(mirrorJob{URL: link, Depth: job.Depth + 1}) // Push the discovered link back onto the queue
+	}
+}
+
+// discoverLinks tokenizes an HTML document and returns every href/src URL it contains,
+// resolved against base.
+func discoverLinks(body []byte, base string) []string {
+	baseURL, err := url.Parse(base) // Parse the base URL so relative links can be resolved
+	if err != nil {                 // If the base itself doesn't parse, nothing can be resolved
+		return nil // Give up on link discovery for this page
+	}
+
+	var links []string                                              // Collected, resolved links
+	tokenizer := html.NewTokenizer(strings.NewReader(string(body))) // Tokenize the document
+	for {                                                           // Walk every token in the document
+		tokenType := tokenizer.Next() // Advance to the next token
+		if tokenType == html.ErrorToken {
+			return links // End of document (or a parse error); return what we have
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue // Only start tags can carry href/src attributes
+		}
+
+		token := tokenizer.Token()        // Materialize the current tag
+		for _, attr := range token.Attr { // Scan its attributes
+			if attr.Key != "href" && attr.Key != "src" { // Only interested in link-bearing attributes
+				continue // Skip anything else
+			}
+			resolved, err := baseURL.Parse(attr.Val) // Resolve relative to the page's own URL
+			if err != nil {                          // Skip anything that doesn't resolve
+				continue // Move to the next attribute
+			}
+			links = append(links, resolved.String()) // Record the resolved link
+		}
+	}
+}
+
+// mirrorPathFor turns a URL into a filesystem path under outDir that mirrors its host and path.
+func mirrorPathFor(outDir, rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL) // Parse the URL
+	if err != nil {                  // If it doesn't parse
+		return "", err // Propagate the error
+	}
+
+	urlPath := parsed.Path                                // The URL's path component
+	if urlPath == "" || strings.HasSuffix(urlPath, "/") { // Treat directory-like paths as index files
+		urlPath += "index.html" // Mirror them to an index.html under that directory
+	}
+
+	dest := filepath.Join(outDir, parsed.Hostname(), filepath.FromSlash(urlPath)) // Join host and path under outDir
+
+	absOutDir, err := filepath.Abs(outDir) // Resolve so e.g. "." and "./" compare equal to their absolute form
+	if err != nil {                        // Essentially unreachable (Abs only fails if Getwd fails)
+		return "", fmt.Errorf("could not resolve output directory %q: %v", outDir, err) // Propagate the error
+	}
+	absDest, err := filepath.Abs(dest) // Resolve dest the same way before comparing
+	if err != nil {
+		return "", fmt.Errorf("could not resolve mirror path for %q: %v", rawURL, err) // Propagate the error
+	}
+
+	rel, err := filepath.Rel(absOutDir, absDest)                                            // How dest sits relative to outDir
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) { // Guard against ZipSlip-style escapes via ".." in the URL path
+		return "", fmt.Errorf("mirror path %q for %q escapes output directory %q", dest, rawURL, outDir) // Refuse to write outside outDir
+	}
+
+	return dest, nil // Safe to write under outDir
+}
@@ -0,0 +1,104 @@
+package main // Declare the main package
+
+import (
+	"os"            // For changing into a temp dir to exercise outDir="."
+	"path/filepath" // For building expected paths
+	"strings"       // For asserting on error messages
+	"testing"       // Standard testing package
+)
+
+// TestMirrorPathForRejectsEscape guards mirrorPathFor's path-traversal check: a URL path
+// that tries to climb out of outDir via ".." must be refused rather than resolved, while an
+// ordinary outDir (including the natural "-out ." case) must still resolve to a real path.
+func TestMirrorPathForRejectsEscape(t *testing.T) {
+	tests := []struct {
+		name        string
+		outDirIsCWD bool // If true, outDir is "." and the test runs inside a fresh temp dir
+		rawURL      string
+		wantErr     bool
+	}{
+		{
+			name:    "ordinary path stays under outDir",
+			rawURL:  "http://documentcloud.org/doc.pdf",
+			wantErr: false,
+		},
+		{
+			name:    "dot-dot segments escape outDir",
+			rawURL:  "http://documentcloud.org/../../etc/passwd",
+			wantErr: true,
+		},
+		{
+			name:        "outDir=. mirrors into the current directory",
+			outDirIsCWD: true,
+			rawURL:      "http://documentcloud.org/doc.pdf",
+			wantErr:     false,
+		},
+		{
+			name:        "outDir=. still rejects an escaping path",
+			outDirIsCWD: true,
+			rawURL:      "http://documentcloud.org/../../etc/passwd",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := filepath.Join(t.TempDir(), "mirror")
+			wantPrefix := outDir + string(filepath.Separator)
+
+			if tt.outDirIsCWD { // Exercise the literal "-out ." case from a fresh working directory
+				cwd, err := os.Getwd()
+				if err != nil {
+					t.Fatalf("could not get working directory: %v", err)
+				}
+				if err := os.Chdir(t.TempDir()); err != nil {
+					t.Fatalf("could not chdir into temp dir: %v", err)
+				}
+				t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+				outDir = "."
+				wantPrefix = "" // dest is relative to outDir in this case; just check no error/escape below
+			}
+
+			dest, err := mirrorPathFor(outDir, tt.rawURL)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("mirrorPathFor(%q, %q) = %q, nil; want an error", outDir, tt.rawURL, dest)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("mirrorPathFor(%q, %q) returned unexpected error: %v", outDir, tt.rawURL, err)
+			}
+			if wantPrefix != "" && !strings.HasPrefix(dest, wantPrefix) {
+				t.Errorf("mirrorPathFor(%q, %q) = %q, not under outDir %q", outDir, tt.rawURL, dest, outDir)
+			}
+		})
+	}
+}
+
+// TestJobQueuePushNeverBlocksDuringPop exercises the scenario the chunk0-1 review comment
+// called out: a worker pushing newly discovered jobs while every worker is mid-push must
+// not deadlock the pool, since push is documented to never block.
+func TestJobQueuePushNeverBlocksDuringPop(t *testing.T) {
+	q := newJobQueue()
+
+	const fanout = 500
+	for i := 0; i < fanout; i++ { // Simulate a single page fanning out to many discovered links
+		q.push(mirrorJob{URL: "http://documentcloud.org/doc", Depth: 1})
+	}
+	q.close()
+
+	got := 0
+	for {
+		if _, ok := q.pop(); !ok {
+			break
+		}
+		got++
+	}
+
+	if got != fanout {
+		t.Errorf("drained %d jobs, want %d", got, fanout)
+	}
+}
@@ -0,0 +1,118 @@
+package main // Declare the main package
+
+import ( // Import necessary packages
+	"encoding/csv"  // For -format=csv output
+	"encoding/json" // For -format=json output
+	"fmt"           // For formatted errors
+	"os"            // For creating the output file
+	"sort"          // For -sort and for a stable record ordering
+)
+
+const ( // Supported values for -format
+	formatTxt  = "txt"  // One URL per line (the original behavior)
+	formatJSON = "json" // A JSON array of {url, host, source_file, line_number} objects
+	formatCSV  = "csv"  // A CSV file with the same fields as columns
+)
+
+// outputFileNameFor returns the base output filename to use for format, with an extension
+// that matches the selected encoding so downstream consumers can dispatch on it.
+func outputFileNameFor(format string) string {
+	switch format { // Dispatch on the requested format
+	case formatJSON:
+		return "extracted_urls.json"
+	case formatCSV:
+		return "extracted_urls.csv"
+	default:
+		return "extracted_urls.txt"
+	}
+}
+
+// sortRecords sorts records by URL in place, for -sort.
+func sortRecords(records []urlRecord) {
+	sort.Slice(records, func(i, j int) bool { // Compare by URL
+		return records[i].URL < records[j].URL // Ascending lexical order
+	})
+}
+
+// recordURLs returns just the URL field of every record, in order, for callers (like
+// saveURLsToFile and mirrorURLs) that only care about the plain URL strings.
+func recordURLs(records []urlRecord) []string {
+	urls := make([]string, 0, len(records)) // Preallocate for the known size
+	for _, record := range records {        // Walk every record
+		urls = append(urls, record.URL) // Collect just the URL
+	}
+	return urls // Return the flattened list
+}
+
+// writeRecords writes records to outputFile in the given format (txt, json, or csv).
+func writeRecords(records []urlRecord, outputFile, format string) error {
+	switch format { // Dispatch on the requested format
+	case formatJSON:
+		return writeRecordsJSON(records, outputFile) // Structured JSON objects
+	case formatCSV:
+		return writeRecordsCSV(records, outputFile) // Structured CSV rows
+	default:
+		return saveURLsToFile(recordURLs(records), outputFile) // Plain one-URL-per-line text
+	}
+}
+
+// writeRecordsJSON writes records to outputFile as a JSON array of objects with
+// url, host, source_file, and line_number fields.
+func writeRecordsJSON(records []urlRecord, outputFile string) error {
+	file, err := os.Create(outputFile) // Create (or truncate) the output file
+	if err != nil {                    // Handle error
+		return fmt.Errorf("could not create output file %s: %v", outputFile, err) // Return formatted error
+	}
+	defer file.Close() // Close the file when done
+
+	type jsonRecord struct { // Explicit JSON field names, independent of the Go struct field names
+		URL        string `json:"url"`
+		Host       string `json:"host"`
+		SourceFile string `json:"source_file"`
+		LineNumber int    `json:"line_number"`
+	}
+
+	jsonRecords := make([]jsonRecord, 0, len(records)) // Build the array to marshal
+	for _, record := range records {                   // Walk every record
+		jsonRecords = append(jsonRecords, jsonRecord{ // Translate field names
+			URL:        record.URL,
+			Host:       record.Host,
+			SourceFile: record.SourceFile,
+			LineNumber: record.LineNumber,
+		})
+	}
+
+	encoder := json.NewEncoder(file)                    // Stream-encode straight to the file
+	encoder.SetIndent("", "  ")                         // Human-readable output
+	if err := encoder.Encode(jsonRecords); err != nil { // Write the array
+		return fmt.Errorf("error writing JSON to output file: %v", err) // Return formatted error
+	}
+
+	return nil // Success
+}
+
+// writeRecordsCSV writes records to outputFile as CSV with a url, host, source_file,
+// line_number header row.
+func writeRecordsCSV(records []urlRecord, outputFile string) error {
+	file, err := os.Create(outputFile) // Create (or truncate) the output file
+	if err != nil {                    // Handle error
+		return fmt.Errorf("could not create output file %s: %v", outputFile, err) // Return formatted error
+	}
+	defer file.Close() // Close the file when done
+
+	writer := csv.NewWriter(file) // Buffered CSV writer
+
+	if err := writer.Write([]string{"url", "host", "source_file", "line_number"}); err != nil { // Header row
+		return fmt.Errorf("error writing CSV header: %v", err) // Return formatted error
+	}
+
+	for _, record := range records { // Walk every record
+		row := []string{record.URL, record.Host, record.SourceFile, fmt.Sprintf("%d", record.LineNumber)} // One row per record
+		if err := writer.Write(row); err != nil {                                                         // Write it
+			return fmt.Errorf("error writing CSV row for %s: %v", record.URL, err) // Return formatted error
+		}
+	}
+
+	writer.Flush()        // Flush the buffer to the file
+	return writer.Error() // Report any error encountered while flushing
+}
@@ -0,0 +1,119 @@
+package main // Declare the main package
+
+import (
+	"encoding/csv"  // For decoding the -format=csv output back into rows
+	"encoding/json" // For decoding the -format=json output back into objects
+	"os"            // For reading the written output file back
+	"path/filepath" // For building the output path
+	"strconv"       // For building the expected line-number column
+	"testing"       // Standard testing package
+)
+
+// testRecords is a small, fixed set of records shared by the encode/decode checks below.
+func testRecords() []urlRecord {
+	return []urlRecord{
+		{URL: "http://documentcloud.org/doc1.pdf", Host: "documentcloud.org", SourceFile: "a.tsv", LineNumber: 1},
+		{URL: "http://documentcloud.org/doc2.pdf", Host: "documentcloud.org", SourceFile: "b.tsv", LineNumber: 3},
+	}
+}
+
+// TestWriteRecordsTxt checks the plain one-URL-per-line encoding used by -format=txt.
+func TestWriteRecordsTxt(t *testing.T) {
+	records := testRecords()
+	path := filepath.Join(t.TempDir(), "out.txt")
+
+	if err := writeRecords(records, path, formatTxt); err != nil {
+		t.Fatalf("writeRecords(txt) returned error: %v", err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read output file: %v", err)
+	}
+
+	want := "http://documentcloud.org/doc1.pdf\nhttp://documentcloud.org/doc2.pdf\n"
+	if string(contents) != want {
+		t.Errorf("got %q, want %q", string(contents), want)
+	}
+}
+
+// TestWriteRecordsJSON checks the -format=json encoding round-trips every field.
+func TestWriteRecordsJSON(t *testing.T) {
+	records := testRecords()
+	path := filepath.Join(t.TempDir(), "out.json")
+
+	if err := writeRecords(records, path, formatJSON); err != nil {
+		t.Fatalf("writeRecords(json) returned error: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("could not open output file: %v", err)
+	}
+	defer file.Close()
+
+	var decoded []struct {
+		URL        string `json:"url"`
+		Host       string `json:"host"`
+		SourceFile string `json:"source_file"`
+		LineNumber int    `json:"line_number"`
+	}
+	if err := json.NewDecoder(file).Decode(&decoded); err != nil {
+		t.Fatalf("could not decode JSON output: %v", err)
+	}
+
+	if len(decoded) != len(records) {
+		t.Fatalf("decoded %d records, want %d", len(decoded), len(records))
+	}
+	for i, record := range records {
+		if decoded[i].URL != record.URL || decoded[i].Host != record.Host ||
+			decoded[i].SourceFile != record.SourceFile || decoded[i].LineNumber != record.LineNumber {
+			t.Errorf("decoded[%d] = %+v, want %+v", i, decoded[i], record)
+		}
+	}
+}
+
+// TestWriteRecordsCSV checks the -format=csv encoding round-trips every field, including
+// the header row.
+func TestWriteRecordsCSV(t *testing.T) {
+	records := testRecords()
+	path := filepath.Join(t.TempDir(), "out.csv")
+
+	if err := writeRecords(records, path, formatCSV); err != nil {
+		t.Fatalf("writeRecords(csv) returned error: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("could not open output file: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("could not decode CSV output: %v", err)
+	}
+
+	wantHeader := []string{"url", "host", "source_file", "line_number"}
+	if len(rows) != len(records)+1 {
+		t.Fatalf("got %d rows (incl. header), want %d", len(rows), len(records)+1)
+	}
+	if len(rows[0]) != len(wantHeader) {
+		t.Fatalf("header row = %v, want %v", rows[0], wantHeader)
+	}
+	for i, want := range wantHeader {
+		if rows[0][i] != want {
+			t.Errorf("header[%d] = %q, want %q", i, rows[0][i], want)
+		}
+	}
+
+	for i, record := range records {
+		row := rows[i+1]
+		wantRow := []string{record.URL, record.Host, record.SourceFile, strconv.Itoa(record.LineNumber)}
+		for j, want := range wantRow {
+			if row[j] != want {
+				t.Errorf("row %d col %d = %q, want %q", i, j, row[j], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package main // Declare the main package
+
+// urlRecord pairs an extracted URL with where it came from, so that -format=json/csv
+// output can carry that provenance through to downstream consumers.
+type urlRecord struct {
+	URL        string // The extracted, validated URL
+	Host       string // The URL's hostname, filled in once cleanURLs accepts it
+	SourceFile string // The file (or "archive:entry.tsv" label) the URL was found in
+	LineNumber int    // The line within SourceFile the URL was found on
+}
@@ -0,0 +1,86 @@
+package main // Declare the main package
+
+import ( // Import necessary packages
+	"log"  // For progress and error logging
+	"sync" // For the collector's dedup map and the worker/collector WaitGroups
+)
+
+// progressInterval controls how often scanTSVFiles logs a "processed N files" line.
+const progressInterval = 100
+
+// scanResult carries one file's extracted URL records (or error) from a worker to the collector.
+type scanResult struct {
+	fileName string      // The file that was scanned
+	records  []urlRecord // URL records extracted from it, if any
+	err      error       // Non-nil if extraction failed
+}
+
+// scanTSVFiles extracts URLs from every file in fileNames using a pool of workers
+// goroutines, deduplicating the results as they stream in rather than materializing
+// the full, duplicated slice in memory. Per-file errors are logged and skipped.
+func scanTSVFiles(fileNames []string, workers int, quiet bool, mode parserMode) []urlRecord {
+	if workers < 1 { // Guard against a nonsensical pool size
+		workers = 1 // Fall back to a single worker
+	}
+
+	files := make(chan string, len(fileNames))  // Feed every file path to the workers up front
+	results := make(chan scanResult, workers*2) // Buffered so workers rarely block handing off results
+
+	for _, fileName := range fileNames { // Load the work queue
+		files <- fileName // One job per file
+	}
+	close(files) // No more files will be added
+
+	var workersWg sync.WaitGroup   // Tracks the extraction workers
+	for i := 0; i < workers; i++ { // Start the worker pool
+		workersWg.Add(1) // One more worker to wait on
+		go func() {      // Each worker drains the files channel until it's closed and empty
+			defer workersWg.Done()        // Signal completion on exit
+			for fileName := range files { // Pull the next file off the queue
+				var records []urlRecord      // URL records extracted from this file (or archive)
+				var err error                // Any extraction error
+				if isArchiveFile(fileName) { // Archives contain zero or more .tsv entries
+					records, err = extractURLsFromArchive(fileName, mode) // Stream its .tsv entries through extractURLsFromFile
+				} else { // A plain .tsv file
+					records, err = extractURLsFromDiskFile(fileName, mode) // Extract directly from disk
+				}
+				results <- scanResult{fileName: fileName, records: records, err: err} // Hand the result to the collector
+			}
+		}()
+	}
+
+	go func() { // Closer goroutine: once every worker has exited, there are no more results coming
+		workersWg.Wait() // Wait for all workers to finish
+		close(results)   // Signal the collector there is no more work
+	}()
+
+	seen := make(map[string]urlRecord) // Streaming dedup set, keyed by URL, filled in as results arrive
+	processed := 0                     // Count of files processed so far, for progress logging
+
+	for result := range results { // Collect results as workers produce them
+		processed++            // One more file accounted for
+		if result.err != nil { // If this file failed to scan
+			log.Printf("Error extracting URLs from file %s: %v", result.fileName, result.err) // Log and continue
+		} else {
+			if !quiet { // Respect -quiet
+				log.Printf("Extracting URLs from file: %s", result.fileName) // Log the file being processed
+			}
+			for _, record := range result.records { // Insert each record into the dedup set
+				if _, exists := seen[record.URL]; !exists { // Keep the first occurrence of each URL
+					seen[record.URL] = record // Remember its source
+				}
+			}
+		}
+
+		if !quiet && processed%progressInterval == 0 { // Periodically report overall progress
+			log.Printf("Processed %d/%d files", processed, len(fileNames)) // Progress line
+		}
+	}
+
+	allRecords := make([]urlRecord, 0, len(seen)) // Flatten the dedup set back into a slice
+	for _, record := range seen {                 // Iterate the set
+		allRecords = append(allRecords, record) // Collect each unique record
+	}
+
+	return allRecords // Return the deduplicated URL records
+}
@@ -0,0 +1,65 @@
+package main // Declare the main package
+
+import (
+	"os"            // For writing temp .tsv fixtures
+	"path/filepath" // For building fixture paths
+	"sort"          // For comparing the result set order-independently
+	"testing"       // Standard testing package
+	"time"          // For the deadlock-detection timeout
+)
+
+// TestScanTSVFilesDedupesAcrossWorkers feeds several small .tsv files with overlapping
+// URLs through scanTSVFiles with more than one worker and checks both that duplicates are
+// collapsed and that the call actually returns rather than hanging, the way concurrency
+// bugs in this series (mirror.go's bounded-channel deadlock, 4be9ae2) have before.
+func TestScanTSVFilesDedupesAcrossWorkers(t *testing.T) {
+	dir := t.TempDir()
+
+	fixtures := map[string]string{
+		"a.tsv": "http://documentcloud.org/doc1.pdf\thttp://documentcloud.org/doc2.pdf\n",
+		"b.tsv": "http://documentcloud.org/doc2.pdf\n", // Duplicate of a row in a.tsv
+		"c.tsv": "http://documentcloud.org/doc3.pdf\n",
+	}
+
+	var fileNames []string
+	for name, contents := range fixtures {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatalf("could not write fixture %s: %v", name, err)
+		}
+		fileNames = append(fileNames, path)
+	}
+
+	done := make(chan []urlRecord, 1)
+	go func() {
+		done <- scanTSVFiles(fileNames, 4, true, parserRegex) // workers > 1 to exercise the pool
+	}()
+
+	var records []urlRecord
+	select {
+	case records = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scanTSVFiles did not return within 5s; suspected deadlock")
+	}
+
+	var gotURLs []string
+	for _, record := range records {
+		gotURLs = append(gotURLs, record.URL)
+	}
+	sort.Strings(gotURLs)
+
+	wantURLs := []string{
+		"http://documentcloud.org/doc1.pdf",
+		"http://documentcloud.org/doc2.pdf",
+		"http://documentcloud.org/doc3.pdf",
+	}
+
+	if len(gotURLs) != len(wantURLs) {
+		t.Fatalf("got %d unique URLs %v, want %d %v", len(gotURLs), gotURLs, len(wantURLs), wantURLs)
+	}
+	for i, want := range wantURLs {
+		if gotURLs[i] != want {
+			t.Errorf("gotURLs[%d] = %q, want %q", i, gotURLs[i], want)
+		}
+	}
+}
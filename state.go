@@ -0,0 +1,78 @@
+package main // Declare the main package
+
+import ( // Import necessary packages
+	"bufio"   // For reading the state file line by line
+	"fmt"     // For formatted errors
+	"os"      // For opening, creating, and renaming the state file
+	"sort"    // For writing the state file in sorted order
+	"strings" // For trimming blank lines
+)
+
+// loadState reads a sidecar state file of one previously-seen URL per line. A missing
+// file is not an error; it simply means nothing has been seen yet (first run).
+func loadState(path string) (map[string]bool, error) {
+	seen := make(map[string]bool) // URLs emitted by a previous -update run
+
+	file, err := os.Open(path) // Open the state file
+	if err != nil {            // If it couldn't be opened
+		if os.IsNotExist(err) { // A missing state file just means this is the first run
+			return seen, nil // Nothing has been seen yet
+		}
+		return nil, fmt.Errorf("could not open state file %s: %v", path, err) // Any other error is fatal
+	}
+	defer file.Close() // Close the file when done
+
+	scanner := bufio.NewScanner(file) // Scan the file line by line
+	for scanner.Scan() {              // Iterate through each line
+		line := strings.TrimSpace(scanner.Text()) // Trim surrounding whitespace
+		if line != "" {                           // Skip blank lines
+			seen[line] = true // Record that this URL has already been emitted
+		}
+	}
+
+	if err := scanner.Err(); err != nil { // Check for a scanning error
+		return nil, fmt.Errorf("error reading state file %s: %v", path, err) // Propagate it
+	}
+
+	return seen, nil // Return the previously-seen set
+}
+
+// saveStateAtomic writes seen (sorted) to path by writing to a temporary file first and
+// renaming it into place, so a crash mid-write never leaves a truncated state file behind.
+func saveStateAtomic(path string, seen map[string]bool) error {
+	urls := make([]string, 0, len(seen)) // Flatten the set into a slice
+	for url := range seen {              // Walk every seen URL
+		urls = append(urls, url) // Collect it
+	}
+	sort.Strings(urls) // Write the state file in sorted order
+
+	tmpPath := path + ".tmp" // Write to a temp file in the same directory first
+
+	file, err := os.Create(tmpPath) // Create (or truncate) the temp file
+	if err != nil {                 // Handle error
+		return fmt.Errorf("could not create temp state file %s: %v", tmpPath, err) // Return formatted error
+	}
+
+	writer := bufio.NewWriter(file) // Buffered writer for performance
+	for _, url := range urls {      // Iterate through all URLs
+		if _, err := writer.WriteString(url + "\n"); err != nil { // Write each URL on a new line
+			file.Close()                                                // Don't leak the handle on error
+			return fmt.Errorf("error writing temp state file: %v", err) // Return error
+		}
+	}
+
+	if err := writer.Flush(); err != nil { // Flush the buffer
+		file.Close()                                                 // Don't leak the handle on error
+		return fmt.Errorf("error flushing temp state file: %v", err) // Return error
+	}
+
+	if err := file.Close(); err != nil { // Close before renaming
+		return fmt.Errorf("error closing temp state file: %v", err) // Return error
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil { // Atomically replace the real state file
+		return fmt.Errorf("could not rename %s to %s: %v", tmpPath, path, err) // Return formatted error
+	}
+
+	return nil // Success
+}
@@ -0,0 +1,76 @@
+package main // Declare the main package
+
+import (
+	"os"            // For checking the .tmp file is gone after a rename
+	"path/filepath" // For building the state file path
+	"testing"       // Standard testing package
+)
+
+// TestStateRoundTripAcrossUpdateRuns exercises the -update workflow end to end: a first
+// run persists its URLs to the state file, and a second run against a superset of those
+// URLs should only report the newly-added ones as new, while still seeing everything in
+// the reloaded state.
+func TestStateRoundTripAcrossUpdateRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "extracted_urls.state")
+
+	firstRun := []string{
+		"http://documentcloud.org/doc1.pdf",
+		"http://documentcloud.org/doc2.pdf",
+	}
+
+	seen, err := loadState(path) // First run: no state file exists yet
+	if err != nil {
+		t.Fatalf("loadState returned error on missing file: %v", err)
+	}
+	if len(seen) != 0 {
+		t.Fatalf("loadState on a missing file returned %v, want empty", seen)
+	}
+
+	for _, url := range firstRun { // Simulate a run to emit firstRun
+		seen[url] = true
+	}
+	if err := saveStateAtomic(path, seen); err != nil {
+		t.Fatalf("saveStateAtomic returned error: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp state file %s.tmp should not remain after a successful rename", path)
+	}
+
+	previouslySeen, err := loadState(path) // Second run: reload what the first run persisted
+	if err != nil {
+		t.Fatalf("loadState returned error reloading state: %v", err)
+	}
+	for _, url := range firstRun {
+		if !previouslySeen[url] {
+			t.Errorf("reloaded state is missing %q", url)
+		}
+	}
+
+	secondRunAll := append(append([]string{}, firstRun...), "http://documentcloud.org/doc3.pdf") // One new URL
+
+	var newURLs []string
+	for _, url := range secondRunAll { // Mirror main's -update filtering logic
+		if !previouslySeen[url] {
+			newURLs = append(newURLs, url)
+		}
+		previouslySeen[url] = true
+	}
+
+	if len(newURLs) != 1 || newURLs[0] != "http://documentcloud.org/doc3.pdf" {
+		t.Errorf("second run reported new URLs %v, want exactly [http://documentcloud.org/doc3.pdf]", newURLs)
+	}
+
+	if err := saveStateAtomic(path, previouslySeen); err != nil {
+		t.Fatalf("saveStateAtomic returned error on second run: %v", err)
+	}
+
+	finalState, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState returned error reloading final state: %v", err)
+	}
+	for _, url := range secondRunAll {
+		if !finalState[url] {
+			t.Errorf("final state is missing %q", url)
+		}
+	}
+}